@@ -3,48 +3,111 @@ package config
 import (
 	"io/ioutil"
 	"path/filepath"
-	"regexp"
+	"reflect"
 
+	"github.com/antonmedv/expr/vm"
 	"gopkg.in/yaml.v2"
 )
 
+// Canonical resource type keys accepted under the top-level `resources:` map
+// and under each entry of `regions:`. Nukers look themselves up by one of
+// these via Config.ResourceConfig.
+const (
+	ResourceTypeS3           = "s3"
+	ResourceTypeIAMUsers     = "IAMUsers"
+	ResourceTypeEC2          = "ec2"
+	ResourceTypeEBSVolumes   = "ebs-volumes"
+	ResourceTypeELBV2        = "elbv2"
+	ResourceTypeRDSInstances = "rds-instances"
+	ResourceTypeLambda       = "lambda"
+	ResourceTypeECSCluster   = "ecscluster"
+	ResourceTypeNATGateway   = "nat-gateway"
+)
+
 // Config - the config object we pass around
 type Config struct {
+	// Resources holds the filter rules for every supported resource type,
+	// keyed by its canonical resource type (see the ResourceType* constants).
+	Resources map[string]ResourceType `yaml:"resources"`
+
+	// Regions holds per-region overrides of Resources, keyed by AWS region
+	// and then by canonical resource type. A resource type missing from a
+	// region's override falls back to the top-level entry in Resources.
+	Regions map[string]map[string]ResourceType `yaml:"regions"`
+
+	// Deprecated: S3 and IAMUsers predate the generalized `resources:` map.
+	// They are still accepted so existing config files keep working, and are
+	// folded into Resources by UnmarshalYAML.
 	S3       ResourceType `yaml:"s3"`
 	IAMUsers ResourceType `yaml:"IAMUsers"`
 }
 
-type ResourceType struct {
-	IncludeRule FilterRule `yaml:"include"`
-	ExcludeRule FilterRule `yaml:"exclude"`
-}
-
-type FilterRule struct {
-	NamesRE []Expression `yaml:"names_regex"`
-}
-
-type Expression struct {
-	RE regexp.Regexp
-}
-
-// UnmarshalText - Internally used by yaml.Unmarshal to unmarshall an Expression field
-func (expression *Expression) UnmarshalText(data []byte) error {
-	var pattern string
+// UnmarshalYAML - Implements yaml.Unmarshaler so that the legacy `s3:` and
+// `IAMUsers:` top-level keys keep working after the move to the generalized
+// `resources:` map. Any config written against the new schema is unaffected;
+// callers should always read filter rules back out through Resources (or
+// ResourceConfig), never through the deprecated S3/IAMUsers fields directly.
+func (c *Config) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type configAlias Config
 
-	if err := yaml.Unmarshal(data, &pattern); err != nil {
+	var alias configAlias
+	if err := unmarshal(&alias); err != nil {
 		return err
 	}
+	*c = Config(alias)
 
-	re, err := regexp.Compile(pattern)
-	if err != nil {
-		return err
+	if c.Resources == nil {
+		c.Resources = map[string]ResourceType{}
 	}
 
-	expression.RE = *re
+	if _, ok := c.Resources[ResourceTypeS3]; !ok && !isZeroResourceType(c.S3) {
+		c.Resources[ResourceTypeS3] = c.S3
+	}
+	if _, ok := c.Resources[ResourceTypeIAMUsers]; !ok && !isZeroResourceType(c.IAMUsers) {
+		c.Resources[ResourceTypeIAMUsers] = c.IAMUsers
+	}
 
 	return nil
 }
 
+func isZeroResourceType(rt ResourceType) bool {
+	return reflect.DeepEqual(rt, ResourceType{})
+}
+
+// ResourceConfig - Returns the effective ResourceType for the given canonical
+// resource type kind in region: a per-region override from Regions, when one
+// is present, is returned in place of the top-level entry in Resources (its
+// fields are not merged with the top-level entry's).
+//
+// NOTE: this checkout does not contain the aws package, so no nuker currently
+// calls ResourceConfig; wiring it into each nuker's list function is still
+// outstanding.
+func (c *Config) ResourceConfig(kind string, region string) ResourceType {
+	if overrides, ok := c.Regions[region]; ok {
+		if override, ok := overrides[kind]; ok {
+			return override
+		}
+	}
+
+	return c.Resources[kind]
+}
+
+type ResourceType struct {
+	IncludeRule FilterRule     `yaml:"include"`
+	ExcludeRule FilterRule     `yaml:"exclude"`
+	Tags        TagFilterRule  `yaml:"tags"`
+	Time        TimeFilterRule `yaml:"time"`
+
+	// Evaluate is an expr-lang expression (see ShouldIncludeResource) that a
+	// resource must satisfy in addition to the rules above, e.g.
+	// `tags.Environment == "dev" && age > duration("72h")`.
+	Evaluate string `yaml:"evaluate"`
+
+	// compiledEvaluate is the compiled form of Evaluate, populated by
+	// compileExpressions at GetConfig time so compile errors fail fast.
+	compiledEvaluate *vm.Program
+}
+
 // GetConfig - Unmarshall the config file and parse it into a config object.
 func GetConfig(filePath string) (*Config, error) {
 	var configObj Config
@@ -64,41 +127,28 @@ func GetConfig(filePath string) (*Config, error) {
 		return nil, err
 	}
 
-	return &configObj, nil
-}
-
-func matchesInclude(name string, includeREs []*regexp.Regexp) bool {
-	for _, re := range includeREs {
-		if re.MatchString(name) {
-			return true
-		}
-	}
-	return false
-}
-
-func matchesExclude(name string, excludeREs []*regexp.Regexp) bool {
-	for _, re := range excludeREs {
-		if re.MatchString(name) {
-			return false
-		}
+	if err := compileExpressions(&configObj); err != nil {
+		return nil, err
 	}
 
-	return true
+	return &configObj, nil
 }
 
-// ShouldInclude - Checks if a name should be included according to the inclusion and exclusion rules
-func ShouldInclude(name string, includeREs []*regexp.Regexp, excludeNamesREs []*regexp.Regexp) bool {
+// ShouldInclude - Checks if a name should be included according to the inclusion and exclusion rules.
+// include and exclude are compiled filters (see NewCompiledFilter) so regex and glob patterns are
+// matched transparently, without the caller needing to know which kind of pattern matched.
+func ShouldInclude(name string, include CompiledFilter, exclude CompiledFilter) bool {
 	shouldInclude := false
 
-	if len(includeREs) > 0 {
+	if include.hasPatterns() {
 		// If any include rules are specified,
 		// only check to see if an exclude rule matches when an include rule matches the user
-		if matchesInclude(name, includeREs) {
-			shouldInclude = matchesExclude(name, excludeNamesREs)
+		if include.Match(name) {
+			shouldInclude = !exclude.Match(name)
 		}
-	} else if len(excludeNamesREs) > 0 {
+	} else if exclude.hasPatterns() {
 		// Only check to see if an exclude rule matches when there are no include rules defined
-		shouldInclude = matchesExclude(name, excludeNamesREs)
+		shouldInclude = !exclude.Match(name)
 	} else {
 		shouldInclude = true
 	}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+func TestConfigUnmarshalLegacyFieldsFoldIntoResources(t *testing.T) {
+	raw := []byte(`
+s3:
+  include:
+    names_regex:
+      - "^keep-"
+IAMUsers:
+  exclude:
+    names_regex:
+      - "^admin-"
+`)
+
+	var cfg Config
+	if err := yaml.Unmarshal(raw, &cfg); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	if len(cfg.Resources[ResourceTypeS3].IncludeRule.NamesRE) != 1 {
+		t.Errorf("expected legacy s3 field to be folded into Resources[%q]", ResourceTypeS3)
+	}
+	if len(cfg.Resources[ResourceTypeIAMUsers].ExcludeRule.NamesRE) != 1 {
+		t.Errorf("expected legacy IAMUsers field to be folded into Resources[%q]", ResourceTypeIAMUsers)
+	}
+}
+
+func TestConfigUnmarshalResourcesMapTakesPrecedenceOverLegacyField(t *testing.T) {
+	raw := []byte(`
+resources:
+  s3:
+    include:
+      names_regex:
+        - "^from-resources-"
+s3:
+  include:
+    names_regex:
+      - "^from-legacy-"
+`)
+
+	var cfg Config
+	if err := yaml.Unmarshal(raw, &cfg); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	got := cfg.Resources[ResourceTypeS3].IncludeRule.NamesRE[0].RE.String()
+	if got != "^from-resources-" {
+		t.Errorf("expected the resources: map entry to win, got pattern %q", got)
+	}
+}
+
+func TestResourceConfigRegionOverridePrecedence(t *testing.T) {
+	cfg := Config{
+		Resources: map[string]ResourceType{
+			ResourceTypeEC2: {Evaluate: "top-level"},
+		},
+		Regions: map[string]map[string]ResourceType{
+			"us-east-1": {
+				ResourceTypeEC2: {Evaluate: "us-east-1-override"},
+			},
+		},
+	}
+
+	if got := cfg.ResourceConfig(ResourceTypeEC2, "us-east-1").Evaluate; got != "us-east-1-override" {
+		t.Errorf("expected region override to win in us-east-1, got %q", got)
+	}
+
+	if got := cfg.ResourceConfig(ResourceTypeEC2, "eu-west-1").Evaluate; got != "top-level" {
+		t.Errorf("expected top-level entry for a region with no override, got %q", got)
+	}
+}
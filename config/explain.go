@@ -0,0 +1,151 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// RuleType identifies which kind of rule decided a MatchResult.
+type RuleType string
+
+const (
+	RuleTypeInclude    RuleType = "include"
+	RuleTypeExclude    RuleType = "exclude"
+	RuleTypeTag        RuleType = "tag"
+	RuleTypeTime       RuleType = "time"
+	RuleTypeExpression RuleType = "expression"
+	RuleTypeDefault    RuleType = "default"
+)
+
+// MatchResult - the outcome of evaluating a resource against a ResourceType, plus enough
+// detail to explain why, for --explain / --dry-run-report output.
+//
+// NOTE: this checkout does not contain the cmd package, so the --explain / --dry-run-report
+// CLI flag that would call Evaluate and print MatchResult per resource is still outstanding;
+// only this config-side API landed here.
+type MatchResult struct {
+	Included    bool
+	Reason      string
+	MatchedRule string
+	RuleType    RuleType
+}
+
+// ResourceMeta - the attributes of a resource, besides its name, needed to evaluate it
+// against a ResourceType's tag, time, and expression rules.
+type ResourceMeta struct {
+	Region    string
+	Tags      map[string]string
+	CreatedAt time.Time
+}
+
+// Evaluate - Checks name/meta against rt's name, tag, time, and expression rules, in that
+// order, and reports which rule decided the outcome. This is the single source of truth for
+// resource filtering decisions; ShouldIncludeResource just reports Evaluate(...).Included.
+func Evaluate(name string, meta ResourceMeta, rt ResourceType) MatchResult {
+	result, err := evaluateCascade(name, meta, rt)
+	if err != nil {
+		return MatchResult{
+			Included:    false,
+			RuleType:    RuleTypeExpression,
+			MatchedRule: rt.Evaluate,
+			Reason:      fmt.Sprintf("excluded: evaluate expression %q failed: %s", rt.Evaluate, err),
+		}
+	}
+
+	return result
+}
+
+// evaluateCascade - Does the actual name/tag/time/expression cascade for Evaluate and
+// ShouldIncludeResource. Returns an error only when the compiled Evaluate expression itself
+// failed to run; callers decide how to surface that (Evaluate folds it into a MatchResult,
+// ShouldIncludeResource returns it directly).
+func evaluateCascade(name string, meta ResourceMeta, rt ResourceType) (MatchResult, error) {
+	include := NewCompiledFilter(rt.IncludeRule)
+	exclude := NewCompiledFilter(rt.ExcludeRule)
+
+	var includeMatched bool
+
+	if include.hasPatterns() {
+		if !include.Match(name) {
+			return MatchResult{
+				Included: false,
+				RuleType: RuleTypeInclude,
+				Reason:   fmt.Sprintf("kept out: %q did not match any include names rule", name),
+			}, nil
+		}
+		if exclude.Match(name) {
+			return MatchResult{
+				Included:    false,
+				RuleType:    RuleTypeExclude,
+				MatchedRule: "names",
+				Reason:      fmt.Sprintf("excluded: %q matched an exclude names rule", name),
+			}, nil
+		}
+
+		includeMatched = true
+	} else if exclude.hasPatterns() && exclude.Match(name) {
+		return MatchResult{
+			Included:    false,
+			RuleType:    RuleTypeExclude,
+			MatchedRule: "names",
+			Reason:      fmt.Sprintf("excluded: %q matched an exclude names rule", name),
+		}, nil
+	}
+
+	if !ShouldIncludeBasedOnTags(meta.Tags, rt.Tags) {
+		return MatchResult{
+			Included:    false,
+			RuleType:    RuleTypeTag,
+			MatchedRule: "tags",
+			Reason:      fmt.Sprintf("excluded: %q did not satisfy the tag filter", name),
+		}, nil
+	}
+
+	if !ShouldIncludeBasedOnTime(meta.CreatedAt, rt.Time) {
+		return MatchResult{
+			Included:    false,
+			RuleType:    RuleTypeTime,
+			MatchedRule: "time",
+			Reason:      fmt.Sprintf("excluded: %q is outside the configured time window", name),
+		}, nil
+	}
+
+	if rt.compiledEvaluate == nil && rt.Evaluate != "" {
+		program, err := rt.compile()
+		if err != nil {
+			return MatchResult{}, err
+		}
+		rt.compiledEvaluate = program
+	}
+
+	if rt.compiledEvaluate != nil {
+		ctx := ResourceContext{Name: name, Region: meta.Region, Tags: meta.Tags, CreatedAt: meta.CreatedAt}
+
+		included, err := evaluateExpression(ctx, rt)
+		if err != nil {
+			return MatchResult{}, err
+		}
+
+		return MatchResult{
+			Included:    included,
+			RuleType:    RuleTypeExpression,
+			MatchedRule: rt.Evaluate,
+			Reason:      fmt.Sprintf("evaluate expression %q determined this outcome", rt.Evaluate),
+		}, nil
+	}
+
+	if includeMatched {
+		return MatchResult{
+			Included:    true,
+			RuleType:    RuleTypeInclude,
+			MatchedRule: "names",
+			Reason:      fmt.Sprintf("kept: %q matched an include names rule", name),
+		}, nil
+	}
+
+	return MatchResult{
+		Included: true,
+		RuleType: RuleTypeDefault,
+		Reason:   fmt.Sprintf("kept: %q did not match any exclude rule", name),
+	}, nil
+}
@@ -0,0 +1,103 @@
+package config
+
+import "testing"
+
+func TestEvaluateReportsIncludeRuleWhenItKeepsAResource(t *testing.T) {
+	rule := ResourceType{
+		IncludeRule: FilterRule{NamesGlob: []string{"prod-*"}},
+	}
+
+	result := Evaluate("prod-web-1", ResourceMeta{}, rule)
+
+	if !result.Included {
+		t.Fatal("expected the resource to be kept")
+	}
+	if result.RuleType != RuleTypeInclude {
+		t.Errorf("expected RuleType %q, got %q", RuleTypeInclude, result.RuleType)
+	}
+	if result.MatchedRule != "names" {
+		t.Errorf("expected MatchedRule %q, got %q", "names", result.MatchedRule)
+	}
+}
+
+func TestEvaluateReportsExcludeRuleMatch(t *testing.T) {
+	rule := ResourceType{
+		ExcludeRule: FilterRule{NamesGlob: []string{"prod-*"}},
+	}
+
+	result := Evaluate("prod-web-1", ResourceMeta{}, rule)
+
+	if result.Included {
+		t.Fatal("expected the resource to be excluded")
+	}
+	if result.RuleType != RuleTypeExclude {
+		t.Errorf("expected RuleType %q, got %q", RuleTypeExclude, result.RuleType)
+	}
+}
+
+func TestEvaluateReportsTagRuleMatch(t *testing.T) {
+	rule := ResourceType{
+		Tags: TagFilterRule{Exclude: []TagFilter{{Key: "DoNotDelete", Present: boolPtr(true)}}},
+	}
+
+	result := Evaluate("any-name", ResourceMeta{Tags: map[string]string{"DoNotDelete": "true"}}, rule)
+
+	if result.Included {
+		t.Fatal("expected the resource to be excluded by the tag rule")
+	}
+	if result.RuleType != RuleTypeTag {
+		t.Errorf("expected RuleType %q, got %q", RuleTypeTag, result.RuleType)
+	}
+}
+
+func TestEvaluateReportsDefaultWhenNoRuleConfigured(t *testing.T) {
+	result := Evaluate("any-name", ResourceMeta{}, ResourceType{})
+
+	if !result.Included {
+		t.Fatal("expected the resource to be kept by default")
+	}
+	if result.RuleType != RuleTypeDefault {
+		t.Errorf("expected RuleType %q, got %q", RuleTypeDefault, result.RuleType)
+	}
+}
+
+func TestEvaluateReportsExpressionRuleMatch(t *testing.T) {
+	rule := ResourceType{Evaluate: `startsWith(name, "tmp-")`}
+
+	program, err := rule.compile()
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	rule.compiledEvaluate = program
+
+	result := Evaluate("tmp-bucket", ResourceMeta{}, rule)
+
+	if !result.Included {
+		t.Fatal("expected the resource to be kept by the expression")
+	}
+	if result.RuleType != RuleTypeExpression {
+		t.Errorf("expected RuleType %q, got %q", RuleTypeExpression, result.RuleType)
+	}
+	if result.MatchedRule != rule.Evaluate {
+		t.Errorf("expected MatchedRule %q, got %q", rule.Evaluate, result.MatchedRule)
+	}
+}
+
+func TestShouldIncludeResourceAgreesWithEvaluate(t *testing.T) {
+	rule := ResourceType{
+		IncludeRule: FilterRule{NamesGlob: []string{"prod-*"}},
+		Tags:        TagFilterRule{Exclude: []TagFilter{{Key: "DoNotDelete", Present: boolPtr(true)}}},
+	}
+
+	ctx := ResourceContext{Name: "prod-web-1", Tags: map[string]string{"DoNotDelete": "true"}}
+	meta := ResourceMeta{Tags: ctx.Tags}
+
+	included, err := ShouldIncludeResource(ctx, rule)
+	if err != nil {
+		t.Fatalf("ShouldIncludeResource: %v", err)
+	}
+
+	if included != Evaluate(ctx.Name, meta, rule).Included {
+		t.Error("ShouldIncludeResource and Evaluate disagreed on the same resource/rule")
+	}
+}
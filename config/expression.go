@@ -0,0 +1,121 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/antonmedv/expr"
+	"github.com/antonmedv/expr/vm"
+)
+
+// NOTE: this checkout has no go.mod, so the antonmedv/expr version can't be pinned here.
+// A real build of this tree needs a release that both parses call-style helpers like
+// startsWith(...) and still resolves under the github.com/antonmedv/expr import path
+// (the module later renamed itself to github.com/expr-lang/expr) -- pin that version in
+// go.mod when this package gets a real module to live in.
+
+// ResourceContext - the attributes of a single resource available to a
+// ResourceType's Evaluate expression and consulted by ShouldIncludeResource.
+type ResourceContext struct {
+	Name      string
+	Region    string
+	Tags      map[string]string
+	CreatedAt time.Time
+}
+
+// compile - Compiles rt.Evaluate, if set, against the expression environment
+// so later evaluation doesn't re-parse the expression per resource.
+//
+// expr.DisableBuiltin("duration") is required because the env below defines its own
+// "duration" helper (time.ParseDuration) of the same name as expr's builtin; without
+// disabling the builtin first, expr.Compile panics on any non-empty Evaluate expression.
+func (rt ResourceType) compile() (*vm.Program, error) {
+	if rt.Evaluate == "" {
+		return nil, nil
+	}
+
+	return expr.Compile(rt.Evaluate, expr.Env(newExpressionEnv(ResourceContext{})), expr.DisableBuiltin("duration"))
+}
+
+// compileExpressions - Compiles every ResourceType.Evaluate expression in c, including
+// per-region overrides, so a misconfigured expression fails at config load time rather
+// than the first time a resource is evaluated against it.
+func compileExpressions(c *Config) error {
+	for kind, rt := range c.Resources {
+		program, err := rt.compile()
+		if err != nil {
+			return fmt.Errorf("resources.%s.evaluate: %w", kind, err)
+		}
+		rt.compiledEvaluate = program
+		c.Resources[kind] = rt
+	}
+
+	for region, overrides := range c.Regions {
+		for kind, rt := range overrides {
+			program, err := rt.compile()
+			if err != nil {
+				return fmt.Errorf("regions.%s.%s.evaluate: %w", region, kind, err)
+			}
+			rt.compiledEvaluate = program
+			overrides[kind] = rt
+		}
+	}
+
+	return nil
+}
+
+// newExpressionEnv - Builds the map that backs both compile-time type checking
+// (called with a zero ResourceContext) and runtime evaluation of an Evaluate
+// expression: the resource attributes plus the helper functions it may call.
+func newExpressionEnv(ctx ResourceContext) map[string]interface{} {
+	return map[string]interface{}{
+		"name":       ctx.Name,
+		"region":     ctx.Region,
+		"tags":       ctx.Tags,
+		"created_at": ctx.CreatedAt,
+		"age":        time.Since(ctx.CreatedAt),
+		"startsWith": strings.HasPrefix,
+		"endsWith":   strings.HasSuffix,
+		"matches": func(s, pattern string) (bool, error) {
+			return regexp.MatchString(pattern, s)
+		},
+		"duration": time.ParseDuration,
+	}
+}
+
+// ShouldIncludeResource - Checks whether a resource should be included: it must pass the
+// name, tag, and time filters in rule, and, when rule.Evaluate is set, the compiled
+// expression must evaluate to true. This delegates to Evaluate's cascade so the two never
+// drift apart; use Evaluate directly when the matched rule needs to be reported too.
+func ShouldIncludeResource(ctx ResourceContext, rule ResourceType) (bool, error) {
+	meta := ResourceMeta{Region: ctx.Region, Tags: ctx.Tags, CreatedAt: ctx.CreatedAt}
+
+	result, err := evaluateCascade(ctx.Name, meta, rule)
+	if err != nil {
+		return false, fmt.Errorf("evaluating expression for %q: %w", ctx.Name, err)
+	}
+
+	return result.Included, nil
+}
+
+// evaluateExpression - Runs rule's compiled Evaluate expression, if any, against ctx.
+// A rule with no Evaluate expression always evaluates truthy.
+func evaluateExpression(ctx ResourceContext, rule ResourceType) (bool, error) {
+	if rule.compiledEvaluate == nil {
+		return true, nil
+	}
+
+	result, err := expr.Run(rule.compiledEvaluate, newExpressionEnv(ctx))
+	if err != nil {
+		return false, fmt.Errorf("evaluating expression for %q: %w", ctx.Name, err)
+	}
+
+	truthy, ok := result.(bool)
+	if !ok {
+		return false, fmt.Errorf("evaluate expression for %q must return a bool, got %T", ctx.Name, result)
+	}
+
+	return truthy, nil
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestShouldIncludeResourceEvaluatesCompiledExpression(t *testing.T) {
+	rule := ResourceType{Evaluate: `tags.Environment == "dev" && startsWith(name, "tmp-")`}
+
+	program, err := rule.compile()
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	rule.compiledEvaluate = program
+
+	included, err := ShouldIncludeResource(ResourceContext{
+		Name: "tmp-web-1",
+		Tags: map[string]string{"Environment": "dev"},
+	}, rule)
+	if err != nil {
+		t.Fatalf("ShouldIncludeResource: %v", err)
+	}
+	if !included {
+		t.Error("expected a resource matching the expression to be included")
+	}
+
+	included, err = ShouldIncludeResource(ResourceContext{
+		Name: "prod-web-1",
+		Tags: map[string]string{"Environment": "dev"},
+	}, rule)
+	if err != nil {
+		t.Fatalf("ShouldIncludeResource: %v", err)
+	}
+	if included {
+		t.Error("expected a resource not matching the expression to be excluded")
+	}
+}
+
+func TestShouldIncludeResourceUsesAgeAndDurationHelper(t *testing.T) {
+	rule := ResourceType{Evaluate: `age > duration("72h")`}
+
+	program, err := rule.compile()
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	rule.compiledEvaluate = program
+
+	included, err := ShouldIncludeResource(ResourceContext{
+		Name:      "old-bucket",
+		CreatedAt: time.Now().Add(-96 * time.Hour),
+	}, rule)
+	if err != nil {
+		t.Fatalf("ShouldIncludeResource: %v", err)
+	}
+	if !included {
+		t.Error("expected a resource older than the duration to be included")
+	}
+}
+
+func TestShouldIncludeResourceErrorsOnNonBoolExpression(t *testing.T) {
+	rule := ResourceType{Evaluate: `name`}
+
+	program, err := rule.compile()
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	rule.compiledEvaluate = program
+
+	if _, err := ShouldIncludeResource(ResourceContext{Name: "anything"}, rule); err == nil {
+		t.Error("expected an error when the expression does not evaluate to a bool")
+	}
+}
+
+func TestCompileExpressionsSurfacesErrorsAtConfigLoadTime(t *testing.T) {
+	cfg := Config{
+		Resources: map[string]ResourceType{
+			ResourceTypeEC2: {Evaluate: `this is not valid expr syntax &&&`},
+		},
+	}
+
+	if err := compileExpressions(&cfg); err == nil {
+		t.Error("expected a malformed Evaluate expression to fail at config load time")
+	}
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"regexp"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"gopkg.in/yaml.v2"
+)
+
+// FilterRule - a set of name-matching rules. A name matches the rule if it
+// matches any pattern in NamesRE or any glob pattern in NamesGlob.
+type FilterRule struct {
+	NamesRE   []Expression `yaml:"names_regex"`
+	NamesGlob []string     `yaml:"names_glob"`
+}
+
+// UnmarshalYAML - Allows a FilterRule to be written as a single pattern string
+// (e.g. `exclude: "prod-*"`) as shorthand for `exclude: { names_glob: ["prod-*"] }`.
+func (f *FilterRule) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var shorthand string
+	if err := unmarshal(&shorthand); err == nil {
+		f.NamesGlob = []string{shorthand}
+		return nil
+	}
+
+	type filterRuleAlias FilterRule
+
+	var alias filterRuleAlias
+	if err := unmarshal(&alias); err != nil {
+		return err
+	}
+	*f = FilterRule(alias)
+
+	return nil
+}
+
+type Expression struct {
+	RE regexp.Regexp
+}
+
+// UnmarshalText - Internally used by yaml.Unmarshal to unmarshall an Expression field
+func (expression *Expression) UnmarshalText(data []byte) error {
+	var pattern string
+
+	if err := yaml.Unmarshal(data, &pattern); err != nil {
+		return err
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return err
+	}
+
+	expression.RE = *re
+
+	return nil
+}
+
+// CompiledFilter - the runtime form of a FilterRule: regex patterns are already
+// compiled, and glob patterns are kept as strings that doublestar.Match parses
+// on each Match call.
+//
+// NOTE: this checkout does not contain the aws package, so no nuker currently builds or
+// passes through a CompiledFilter; that wiring is still outstanding.
+type CompiledFilter struct {
+	regexes []*regexp.Regexp
+	globs   []string
+}
+
+// NewCompiledFilter - Compiles a FilterRule into a CompiledFilter.
+func NewCompiledFilter(rule FilterRule) CompiledFilter {
+	compiled := CompiledFilter{
+		globs: rule.NamesGlob,
+	}
+
+	for _, expr := range rule.NamesRE {
+		re := expr.RE
+		compiled.regexes = append(compiled.regexes, &re)
+	}
+
+	return compiled
+}
+
+// Match - Returns true if name matches any regex or glob pattern in the filter.
+func (f CompiledFilter) Match(name string) bool {
+	for _, re := range f.regexes {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+
+	for _, pattern := range f.globs {
+		if ok, _ := doublestar.Match(pattern, name); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (f CompiledFilter) hasPatterns() bool {
+	return len(f.regexes) > 0 || len(f.globs) > 0
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+func TestCompiledFilterMatchesGlobAndRegex(t *testing.T) {
+	rule := FilterRule{
+		NamesGlob: []string{"prod-*"},
+	}
+	rule.NamesRE = append(rule.NamesRE, mustExpression(t, "^legacy-[0-9]+$"))
+
+	filter := NewCompiledFilter(rule)
+
+	cases := map[string]bool{
+		"prod-web-1":  true,
+		"legacy-42":   true,
+		"legacy-abc":  false,
+		"staging-web": false,
+	}
+
+	for name, want := range cases {
+		if got := filter.Match(name); got != want {
+			t.Errorf("Match(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestFilterRuleUnmarshalShorthandString(t *testing.T) {
+	var rule FilterRule
+	if err := yaml.Unmarshal([]byte(`"prod-*"`), &rule); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	if len(rule.NamesGlob) != 1 || rule.NamesGlob[0] != "prod-*" {
+		t.Fatalf("expected shorthand string to populate NamesGlob, got %+v", rule)
+	}
+}
+
+func TestFilterRuleUnmarshalFullForm(t *testing.T) {
+	raw := []byte(`
+names_regex:
+  - "^prod-"
+names_glob:
+  - "tmp-*"
+`)
+
+	var rule FilterRule
+	if err := yaml.Unmarshal(raw, &rule); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	if len(rule.NamesRE) != 1 || len(rule.NamesGlob) != 1 {
+		t.Fatalf("expected both names_regex and names_glob to be populated, got %+v", rule)
+	}
+}
+
+func mustExpression(t *testing.T, pattern string) Expression {
+	t.Helper()
+
+	var expression Expression
+	if err := expression.UnmarshalText([]byte(pattern)); err != nil {
+		t.Fatalf("compiling expression %q: %v", pattern, err)
+	}
+
+	return expression
+}
@@ -0,0 +1,80 @@
+package config
+
+import "github.com/bmatcuk/doublestar/v4"
+
+// TagFilterRule - tag-based include/exclude filtering for a resource type.
+// A resource's tags must satisfy every entry in Include (AND across entries)
+// and must not satisfy any entry in Exclude (OR across entries).
+type TagFilterRule struct {
+	Include []TagFilter `yaml:"include"`
+	Exclude []TagFilter `yaml:"exclude"`
+}
+
+// TagFilter - a single tag condition. Precedence when more than one of
+// ValueRegex/ValueGlob/Present is set is ValueRegex, then ValueGlob, then
+// Present; with none set, the entry matches on key presence alone. Present is
+// a pointer so `present: false` (assert the key is absent) can be told apart
+// from leaving it unset (fall back to plain key presence).
+type TagFilter struct {
+	Key        string      `yaml:"key"`
+	ValueRegex *Expression `yaml:"value_regex"`
+	ValueGlob  string      `yaml:"value_glob"`
+	Present    *bool       `yaml:"present"`
+}
+
+func (f TagFilter) matches(tags map[string]string) bool {
+	value, ok := tags[f.Key]
+
+	switch {
+	case f.ValueRegex != nil:
+		return ok && f.ValueRegex.RE.MatchString(value)
+	case f.ValueGlob != "":
+		if !ok {
+			return false
+		}
+		matched, _ := doublestar.Match(f.ValueGlob, value)
+		return matched
+	case f.Present != nil:
+		return ok == *f.Present
+	default:
+		return ok
+	}
+}
+
+// ShouldIncludeBasedOnTags - Checks if a resource's tags should be included according to
+// the tag-based inclusion and exclusion rules in rule.
+//
+// NOTE: this checkout does not contain the aws package, so no nuker's list function
+// currently calls this before printing its delete plan; that wiring (S3, EC2, EBS, RDS,
+// Lambda, etc., per the original request) is still outstanding.
+func ShouldIncludeBasedOnTags(tags map[string]string, rule TagFilterRule) bool {
+	for _, filter := range rule.Include {
+		if !filter.matches(tags) {
+			return false
+		}
+	}
+
+	for _, filter := range rule.Exclude {
+		if filter.matches(tags) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// TagsFromPointerMap - Normalises the map[string]*string tag shape returned by
+// several AWS SDK tagging APIs (e.g. Lambda's ListTags) into a plain
+// map[string]string for use with ShouldIncludeBasedOnTags.
+func TagsFromPointerMap(tags map[string]*string) map[string]string {
+	normalised := make(map[string]string, len(tags))
+
+	for key, value := range tags {
+		if value == nil {
+			continue
+		}
+		normalised[key] = *value
+	}
+
+	return normalised
+}
@@ -0,0 +1,66 @@
+package config
+
+import "testing"
+
+func boolPtr(b bool) *bool { return &b }
+
+func TestShouldIncludeBasedOnTagsIncludeIsAndedAcrossEntries(t *testing.T) {
+	rule := TagFilterRule{
+		Include: []TagFilter{
+			{Key: "Environment", ValueGlob: "prod"},
+			{Key: "owner", ValueGlob: "team-*"},
+		},
+	}
+
+	if !ShouldIncludeBasedOnTags(map[string]string{"Environment": "prod", "owner": "team-x"}, rule) {
+		t.Error("expected a resource matching every include entry to be included")
+	}
+
+	if ShouldIncludeBasedOnTags(map[string]string{"Environment": "prod"}, rule) {
+		t.Error("expected a resource missing one include entry to be excluded")
+	}
+}
+
+func TestShouldIncludeBasedOnTagsExcludeIsOredAcrossEntries(t *testing.T) {
+	rule := TagFilterRule{
+		Exclude: []TagFilter{
+			{Key: "DoNotDelete", Present: boolPtr(true)},
+			{Key: "Environment", ValueGlob: "prod"},
+		},
+	}
+
+	if ShouldIncludeBasedOnTags(map[string]string{"DoNotDelete": "true"}, rule) {
+		t.Error("expected a resource matching any exclude entry to be excluded")
+	}
+	if ShouldIncludeBasedOnTags(map[string]string{"Environment": "prod"}, rule) {
+		t.Error("expected a resource matching any exclude entry to be excluded")
+	}
+	if !ShouldIncludeBasedOnTags(map[string]string{"Environment": "dev"}, rule) {
+		t.Error("expected a resource matching no exclude entry to be included")
+	}
+}
+
+func TestTagFilterPresentDistinguishesAbsenceFromUnset(t *testing.T) {
+	requireAbsent := TagFilter{Key: "DoNotDelete", Present: boolPtr(false)}
+
+	if requireAbsent.matches(map[string]string{"DoNotDelete": "true"}) {
+		t.Error("present: false should not match when the tag key exists")
+	}
+	if !requireAbsent.matches(map[string]string{}) {
+		t.Error("present: false should match when the tag key is absent")
+	}
+
+	requirePresent := TagFilter{Key: "DoNotDelete", Present: boolPtr(true)}
+	if !requirePresent.matches(map[string]string{"DoNotDelete": "true"}) {
+		t.Error("present: true should match when the tag key exists")
+	}
+
+	// With Present unset, the entry falls back to plain key-presence matching.
+	unset := TagFilter{Key: "DoNotDelete"}
+	if !unset.matches(map[string]string{"DoNotDelete": "anything"}) {
+		t.Error("an entry with no value constraint and no Present should match on key presence")
+	}
+	if unset.matches(map[string]string{}) {
+		t.Error("an entry with no value constraint and no Present should not match when the key is absent")
+	}
+}
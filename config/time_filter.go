@@ -0,0 +1,66 @@
+package config
+
+import "time"
+
+// Duration - a time.Duration that unmarshals from a YAML string (e.g. "72h"),
+// since time.Duration has no native YAML string representation.
+type Duration struct {
+	time.Duration
+}
+
+// UnmarshalYAML - Internally used by yaml.Unmarshal to unmarshall a Duration field
+func (d *Duration) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var raw string
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+
+	parsed, err := time.ParseDuration(raw)
+	if err != nil {
+		return err
+	}
+
+	d.Duration = parsed
+
+	return nil
+}
+
+// TimeFilterRule - age/time-window filtering for a resource type, based on
+// each resource's creation timestamp. CreatedAfter/CreatedBefore are absolute
+// bounds; MinAge/MaxAge are relative to now. When either absolute bound is
+// set, the relative bounds are ignored entirely. All bounds are inclusive.
+type TimeFilterRule struct {
+	CreatedAfter  *time.Time `yaml:"created_after"`
+	CreatedBefore *time.Time `yaml:"created_before"`
+	MinAge        *Duration  `yaml:"min_age"`
+	MaxAge        *Duration  `yaml:"max_age"`
+}
+
+// ShouldIncludeBasedOnTime - Checks if a resource created at createdAt should be included
+// according to the absolute and relative age bounds in rule.
+//
+// NOTE: this checkout does not contain the aws package, so no lister currently calls this
+// with a resource's creation timestamp, and skipped resources aren't yet reported in summary
+// output as the original request asked; both are still outstanding.
+func ShouldIncludeBasedOnTime(createdAt time.Time, rule TimeFilterRule) bool {
+	if rule.CreatedAfter != nil || rule.CreatedBefore != nil {
+		if rule.CreatedAfter != nil && createdAt.Before(*rule.CreatedAfter) {
+			return false
+		}
+		if rule.CreatedBefore != nil && createdAt.After(*rule.CreatedBefore) {
+			return false
+		}
+
+		return true
+	}
+
+	if rule.MinAge != nil && createdAt.After(time.Now().Add(-rule.MinAge.Duration)) {
+		return false
+	}
+
+	if rule.MaxAge != nil && createdAt.Before(time.Now().Add(-rule.MaxAge.Duration)) {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestShouldIncludeBasedOnTimeAbsoluteBoundsAreInclusive(t *testing.T) {
+	after := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	rule := TimeFilterRule{CreatedAfter: &after, CreatedBefore: &before}
+
+	cases := map[time.Time]bool{
+		after:                         true,  // lower bound is inclusive
+		before:                        true,  // upper bound is inclusive
+		after.Add(-time.Second):       false, // just before the lower bound
+		before.Add(time.Second):       false, // just after the upper bound
+		after.Add(24 * time.Hour):     true,
+	}
+
+	for createdAt, want := range cases {
+		if got := ShouldIncludeBasedOnTime(createdAt, rule); got != want {
+			t.Errorf("ShouldIncludeBasedOnTime(%v) = %v, want %v", createdAt, got, want)
+		}
+	}
+}
+
+func TestShouldIncludeBasedOnTimeAbsoluteBoundsTakePrecedenceOverRelative(t *testing.T) {
+	after := time.Now().Add(-time.Hour)
+	minAge := Duration{Duration: 100 * 365 * 24 * time.Hour} // would exclude everything if honored
+
+	rule := TimeFilterRule{CreatedAfter: &after, MinAge: &minAge}
+
+	if !ShouldIncludeBasedOnTime(time.Now(), rule) {
+		t.Error("expected the absolute CreatedAfter bound to take precedence over MinAge")
+	}
+}
+
+func TestShouldIncludeBasedOnTimeMaxAgeCapsHowOldAResourceMayBe(t *testing.T) {
+	maxAge := Duration{Duration: 24 * time.Hour}
+	rule := TimeFilterRule{MaxAge: &maxAge}
+
+	if ShouldIncludeBasedOnTime(time.Now().Add(-48*time.Hour), rule) {
+		t.Error("expected a resource older than MaxAge to be excluded")
+	}
+	if !ShouldIncludeBasedOnTime(time.Now().Add(-time.Hour), rule) {
+		t.Error("expected a resource younger than MaxAge to be included")
+	}
+}
+
+func TestShouldIncludeBasedOnTimeMinAgeFloorsHowYoungAResourceMayBe(t *testing.T) {
+	minAge := Duration{Duration: 24 * time.Hour}
+	rule := TimeFilterRule{MinAge: &minAge}
+
+	if !ShouldIncludeBasedOnTime(time.Now().Add(-48*time.Hour), rule) {
+		t.Error("expected a resource older than MinAge to be included")
+	}
+	if ShouldIncludeBasedOnTime(time.Now().Add(-time.Hour), rule) {
+		t.Error("expected a resource younger than MinAge to be excluded")
+	}
+}
+
+func TestDurationUnmarshalYAML(t *testing.T) {
+	var d Duration
+	if err := d.UnmarshalYAML(func(out interface{}) error {
+		*out.(*string) = "72h"
+		return nil
+	}); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	if d.Duration != 72*time.Hour {
+		t.Errorf("got %v, want 72h", d.Duration)
+	}
+}